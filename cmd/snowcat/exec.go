@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/tekumara/snowcat/pkg/query"
+)
+
+func newExecCmd(flags *connFlags) *cobra.Command {
+	var (
+		stmtText  string
+		stmtFile  string
+		paramFile string
+		params    []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "exec",
+		Short: "Execute a DDL/DML statement and report the number of rows affected",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openConnection(cmd, flags)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			ctx, cancel := signalHandlerContext(cmd.Context())
+			defer cancel()
+
+			stmt, err := query.ResolveQuery(stmtText, stmtFile)
+			if err != nil {
+				return fmt.Errorf("reading statement: %w", err)
+			}
+
+			bindArgs, err := query.ResolveArgs(params, paramFile)
+			if err != nil {
+				return fmt.Errorf("parsing --param: %w", err)
+			}
+
+			log.Debug().Str("statement", stmt).Int("params", len(bindArgs)).Msg("Executing statement against snowflake")
+			result, err := db.ExecContext(ctx, stmt, bindArgs...)
+			if err != nil {
+				return fmt.Errorf("executing statement: %w", err)
+			}
+
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("getting rows affected: %w", err)
+			}
+
+			fmt.Printf("%d rows affected\n", affected)
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&stmtText, "query", "", "SQL statement to execute. Falls back to --query.file, then stdin, if not set.")
+	f.StringVar(&stmtFile, "query.file", "", "File containing the SQL statement to execute. Cannot be used in conjunction with --query.")
+	f.StringVar(&paramFile, "param.file", "", "File of additional name=value query parameters, one per line.")
+	f.StringArrayVar(&params, "param", nil, "Named query parameter in the form name=value, bound with sql.Named. Repeatable.")
+
+	return cmd
+}
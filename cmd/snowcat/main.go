@@ -0,0 +1,17 @@
+// Command snowcat queries Snowflake from the command line.
+package main
+
+import (
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	if err := newRootCmd().Execute(); err != nil {
+		log.Fatal().Err(err).Msg("snowcat failed")
+	}
+}
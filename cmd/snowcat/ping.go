@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tekumara/snowcat/pkg/query"
+)
+
+func newPingCmd(flags *connFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "ping",
+		Short: "Check that snowcat can authenticate and reach snowflake",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openConnection(cmd, flags)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			ctx, cancel := signalHandlerContext(cmd.Context())
+			defer cancel()
+
+			latency, err := query.Ping(ctx, db)
+			if err != nil {
+				return fmt.Errorf("pinging snowflake: %w", err)
+			}
+
+			fmt.Printf("ok (%s)\n", latency)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/tekumara/snowcat/pkg/query"
+)
+
+func newQueryCmd(flags *connFlags) *cobra.Command {
+	var (
+		outputFormat   string
+		outputFile     string
+		multiStatement int
+		async          bool
+		queryID        string
+		queryText      string
+		queryFile      string
+		paramFile      string
+		params         []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "query",
+		Short: "Execute a query and write its results",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openConnection(cmd, flags)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			ctx, cancel := signalHandlerContext(cmd.Context())
+			defer cancel()
+
+			ctx, err = query.WithOptions(ctx, query.Options{
+				MultiStatement: multiStatement,
+				QueryID:        queryID,
+			})
+			if err != nil {
+				return err
+			}
+
+			var (
+				queryStr string
+				bindArgs []interface{}
+			)
+			if queryID == "" {
+				queryStr, err = query.ResolveQuery(queryText, queryFile)
+				if err != nil {
+					return fmt.Errorf("reading query: %w", err)
+				}
+
+				bindArgs, err = query.ResolveArgs(params, paramFile)
+				if err != nil {
+					return fmt.Errorf("parsing --param: %w", err)
+				}
+			}
+
+			if async && queryID == "" {
+				id, err := query.QueryAsync(ctx, db, queryStr, bindArgs)
+				if err != nil {
+					return fmt.Errorf("submitting async query: %w", err)
+				}
+				fmt.Println(id)
+				return nil
+			}
+
+			log.Debug().Str("query", queryStr).Int("params", len(bindArgs)).Msg("Querying snowflake")
+			rows, err := db.QueryContext(ctx, queryStr, bindArgs...)
+			if err != nil {
+				return fmt.Errorf("querying snowflake: %w", err)
+			}
+			defer rows.Close()
+
+			// templatedOutputFile means --output.file has a %d verb, so each
+			// statement of a multi-statement query is written to its own file
+			// rather than appending sections to a single stream
+			templatedOutputFile := outputFile != "" && strings.Contains(outputFile, "%d")
+
+			out := io.Writer(os.Stdout)
+			if outputFile != "" && !templatedOutputFile {
+				f, err := os.Create(outputFile)
+				if err != nil {
+					return fmt.Errorf("creating output file %s: %w", outputFile, err)
+				}
+				defer f.Close()
+				out = f
+			}
+
+			// Iterate through every statement's result set (a multi-statement
+			// query has more than one; a single statement always has exactly
+			// one)
+			totalRows := 0
+			for stmt := 0; ; stmt++ {
+				rowCount, err := query.WriteResultSet(rows, out, outputFile, outputFormat, stmt, templatedOutputFile)
+				if err != nil {
+					return fmt.Errorf("writing query results for statement %d: %w", stmt, err)
+				}
+				totalRows += rowCount
+
+				if !rows.NextResultSet() {
+					break
+				}
+			}
+
+			if err := rows.Err(); err != nil {
+				return fmt.Errorf("iterating rows: %w", err)
+			}
+			if err := rows.Close(); err != nil {
+				return fmt.Errorf("closing rows: %w", err)
+			}
+
+			log.Debug().Int("rows", totalRows).Msg("Successfully pulled results from snowflake")
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&outputFormat, "output.format", "csv", "Output format for query results (one of: csv, tsv, json, jsonl, parquet)")
+	f.StringVar(&outputFile, "output.file", "", "File to write output to (default: stdout). If it contains a %d verb, each statement of a multi-statement query is written to its own file.")
+	f.IntVar(&multiStatement, "query.multistatement", 0, "Execute up to N statements in a single request (via gosnowflake.WithMultiStatement), emitting each statement's results as its own output section. 0 disables multi-statement mode.")
+	f.BoolVar(&async, "query.async", false, "Submit the query asynchronously and print its Snowflake query ID immediately instead of waiting for results.")
+	f.StringVar(&queryID, "query.id", "", "Fetch results for a previously submitted Snowflake query ID instead of executing a new query.")
+	f.StringVar(&queryText, "query", "", "SQL query to execute. Falls back to --query.file, then stdin, if not set.")
+	f.StringVar(&queryFile, "query.file", "", "File containing the SQL query to execute. Cannot be used in conjunction with --query.")
+	f.StringVar(&paramFile, "param.file", "", "File of additional name=value query parameters, one per line. Ignored when --query.id is set.")
+	f.StringArrayVar(&params, "param", nil, "Named query parameter in the form name=value, bound with sql.Named. Repeatable.")
+
+	return cmd
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/tekumara/snowcat/pkg/snowconn"
+)
+
+// connFlags are the snowflake.* (and log.level) flags shared by every
+// subcommand that needs a connection, bound as persistent flags on the root
+// command.
+type connFlags struct {
+	account            string
+	host               string
+	port               int
+	protocol           string
+	database           string
+	schema             string
+	warehouse          string
+	user               string
+	password           string
+	role               string
+	privateKeyFile     string
+	privateKeyPasscode string
+	authenticator      string
+	maxRetryCount      int
+	connectionName     string
+	oauthToken         string
+	oauthTokenFile     string
+	logLevel           string
+}
+
+func newRootCmd() *cobra.Command {
+	var flags connFlags
+
+	cmd := &cobra.Command{
+		Use:           "snowcat",
+		Short:         "Query Snowflake from the command line",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return bindEnvVars(cmd)
+		},
+	}
+
+	pf := cmd.PersistentFlags()
+	pf.StringVar(&flags.account, "snowflake.account", "", "Account name for snowflake. Account name is not the username, see https://docs.snowflake.com/en/user-guide/admin-account-identifier for more details")
+	pf.StringVar(&flags.host, "snowflake.host", "", "Host name for snowflake (default: {account}.snowflakecomputing.com)")
+	pf.IntVar(&flags.port, "snowflake.port", 443, "Port for snowflake connection")
+	pf.StringVar(&flags.protocol, "snowflake.protocol", "https", "Protocol for snowflake connection (http or https)")
+	pf.StringVar(&flags.database, "snowflake.database", "", "Database name for snowflake")
+	pf.StringVar(&flags.schema, "snowflake.schema", "", "Schema name for snowflake")
+	pf.StringVar(&flags.warehouse, "snowflake.warehouse", "", "Warehouse name for snowflake")
+	pf.StringVar(&flags.user, "snowflake.user", "", "Username for snowflake")
+	pf.StringVar(&flags.password, "snowflake.password", "", "Password for snowflake. Cannot be used in conjunction with snowflake.private.key.file")
+	pf.StringVar(&flags.role, "snowflake.role", "", "Role for snowflake")
+	pf.StringVar(&flags.privateKeyFile, "snowflake.private.key.file", "", "Location of private key file used to authenticate with snowflake, pkcs8 in PEM format. Cannot be used in conjunction with snowflake.password")
+	pf.StringVar(&flags.privateKeyPasscode, "snowflake.private.key.passcode", "", "Passcode for encrypted private key (not necessary if key is not encrypted)")
+	pf.StringVar(&flags.authenticator, "snowflake.authenticator", "", "Authenticator type for snowflake (one of: externalbrowser, or an Okta URL such as https://<tenant>.okta.com). OAuth is selected via snowflake.oauth.token(.file) instead of this flag.")
+	pf.IntVar(&flags.maxRetryCount, "snowflake.max.retry.count", 7, "Specifies maximum number of subsequent retries with backoff. Use -1 for no retries, as 0 means use the default.")
+	pf.StringVar(&flags.connectionName, "snowflake.connection.name", "", "Name of a connection in connections.toml to use as a base configuration. Individual snowflake.* flags take precedence over the connection's settings.")
+	pf.StringVar(&flags.oauthToken, "snowflake.oauth.token", "", "OAuth bearer token for snowflake. Cannot be used in conjunction with snowflake.oauth.token.file.")
+	pf.StringVar(&flags.oauthTokenFile, "snowflake.oauth.token.file", "", "Location of a file containing an OAuth bearer token for snowflake. Cannot be used in conjunction with snowflake.oauth.token.")
+	pf.StringVar(&flags.logLevel, "log.level", "info", "Log level (one of: off, error, warn, info, debug, trace). Applies to both snowcat and the underlying snowflake driver.")
+
+	cmd.AddCommand(newQueryCmd(&flags), newExecCmd(&flags), newPingCmd(&flags))
+	return cmd
+}
+
+// bindEnvVars sets any flag's value from its environment-variable
+// equivalent (e.g. --snowflake.account becomes SNOWFLAKE_ACCOUNT) when the
+// flag was not explicitly passed on the command line, matching the env var
+// support the previous peterbourgon/ff-based CLI provided.
+func bindEnvVars(cmd *cobra.Command) error {
+	var err error
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if err != nil || f.Changed {
+			return
+		}
+		envName := strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(f.Name))
+		if v, ok := os.LookupEnv(envName); ok {
+			if setErr := f.Value.Set(v); setErr != nil {
+				err = fmt.Errorf("setting --%s from %s: %w", f.Name, envName, setErr)
+			}
+		}
+	})
+	return err
+}
+
+// openConnection resolves flags into a usable *sql.DB: merging in
+// connections.toml, validating, configuring logging, and registering the
+// per-invocation request ID used to correlate driver logs.
+func openConnection(cmd *cobra.Command, flags *connFlags) (*sql.DB, error) {
+	if err := snowconn.ConfigureLogging(flags.logLevel); err != nil {
+		return nil, fmt.Errorf("invalid log.level: %w", err)
+	}
+
+	// Load a named connection from connections.toml (as used by the
+	// Snowflake CLI and other Snowflake tooling) to fill in any
+	// snowflake.* flags that were left unset. SNOWFLAKE_HOME selects the
+	// directory containing connections.toml (default: ~/.snowflake) and
+	// SNOWFLAKE_DEFAULT_CONNECTION_NAME selects the connection to use when
+	// snowflake.connection.name is not given. If neither is set, the user
+	// never asked for a named connection, so connections.toml is skipped
+	// entirely rather than requiring a "default" entry to exist in it.
+	connName := flags.connectionName
+	if connName == "" {
+		connName = os.Getenv("SNOWFLAKE_DEFAULT_CONNECTION_NAME")
+	}
+
+	var conn *snowconn.ConnectionSettings
+	if connName != "" {
+		var err error
+		conn, err = snowconn.LoadConnection(connName, true)
+		if err != nil {
+			return nil, fmt.Errorf("loading connection from connections.toml: %w", err)
+		}
+	}
+
+	cfg := snowconn.Config{
+		Account:            flags.account,
+		Host:               flags.host,
+		Port:               flags.port,
+		Protocol:           flags.protocol,
+		Database:           flags.database,
+		Schema:             flags.schema,
+		Warehouse:          flags.warehouse,
+		User:               flags.user,
+		Password:           flags.password,
+		Role:               flags.role,
+		PrivateKeyFile:     flags.privateKeyFile,
+		PrivateKeyPasscode: flags.privateKeyPasscode,
+		Authenticator:      flags.authenticator,
+		MaxRetryCount:      flags.maxRetryCount,
+		OauthToken:         flags.oauthToken,
+		OauthTokenFile:     flags.oauthTokenFile,
+	}
+	cfg.ApplyConnection(conn, cmd.Flags().Changed("snowflake.port"), cmd.Flags().Changed("snowflake.protocol"))
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	requestID := uuid.NewString()
+	snowconn.RegisterRequestContext(requestID, cfg.User, cfg.Role, cfg.Warehouse)
+	log.Debug().Str("request_id", requestID).Msg("Starting snowcat")
+
+	return cfg.Open()
+}
+
+// signalHandlerContext returns a context that's cancelled when the process
+// receives a SIGINT.
+func signalHandlerContext(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+
+	go func() {
+		// The signal handler is removed after the first signal is processed or
+		// the context is cancelled, which causes the program to revert to the
+		// default signal handling behavior of terminating the program
+		// immediately. The next signal received will therefore cause immediate
+		// termination. If this causes too many accidental terminations, we
+		// could leave the signal handler in place and rely on SIGTERM/SIGKILL
+		// for forcible terminations instead.
+		defer signal.Stop(sigs)
+
+		select {
+		case sig := <-sigs:
+			log.Warn().
+				Str("signal", sig.String()).
+				Msg("Caught signal")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
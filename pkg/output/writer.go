@@ -0,0 +1,309 @@
+// Package output writes a stream of Snowflake query result rows in a
+// particular output format (CSV, TSV, JSON, JSONL or Parquet).
+package output
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// RowWriter writes a stream of query result rows in a particular output
+// format. WriteHeader is called once with the column names before any row,
+// and Close is called once after the last row to flush and finalize the
+// output.
+type RowWriter interface {
+	WriteHeader(columns []string) error
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// NewWriter constructs the RowWriter for the requested format (one of: csv,
+// tsv, json, jsonl, parquet).
+func NewWriter(format string, out io.Writer, columnTypes []*sql.ColumnType) (RowWriter, error) {
+	switch format {
+	case "csv":
+		return newDelimitedRowWriter(out, ','), nil
+	case "tsv":
+		return newDelimitedRowWriter(out, '\t'), nil
+	case "json":
+		return newJSONRowWriter(out, columnTypes, false), nil
+	case "jsonl":
+		return newJSONRowWriter(out, columnTypes, true), nil
+	case "parquet":
+		return newParquetRowWriter(out, columnTypes), nil
+	default:
+		return nil, fmt.Errorf("unsupported output format %q (must be one of: csv, tsv, json, jsonl, parquet)", format)
+	}
+}
+
+// delimitedRowWriter writes rows as CSV or TSV, stringifying every value the
+// same way the original implementation did.
+type delimitedRowWriter struct {
+	w *csv.Writer
+}
+
+func newDelimitedRowWriter(out io.Writer, comma rune) *delimitedRowWriter {
+	w := csv.NewWriter(out)
+	w.Comma = comma
+	return &delimitedRowWriter{w: w}
+}
+
+func (d *delimitedRowWriter) WriteHeader(columns []string) error {
+	return d.w.Write(columns)
+}
+
+func (d *delimitedRowWriter) WriteRow(values []interface{}) error {
+	stringValues := make([]string, len(values))
+	for i, v := range values {
+		stringValues[i] = fmt.Sprint(v)
+	}
+	return d.w.Write(stringValues)
+}
+
+func (d *delimitedRowWriter) Close() error {
+	d.w.Flush()
+	return d.w.Error()
+}
+
+// jsonRowWriter writes rows either as a single JSON array (format "json") or
+// as newline-delimited JSON objects (format "jsonl"). Values are converted
+// with typedJSONValue first so numeric and boolean columns aren't
+// stringified and VARIANT/OBJECT/ARRAY columns are embedded as raw JSON.
+type jsonRowWriter struct {
+	w           io.Writer
+	columnTypes []*sql.ColumnType
+	columns     []string
+	jsonl       bool
+	wroteRow    bool
+}
+
+func newJSONRowWriter(out io.Writer, columnTypes []*sql.ColumnType, jsonl bool) *jsonRowWriter {
+	return &jsonRowWriter{w: out, columnTypes: columnTypes, jsonl: jsonl}
+}
+
+func (j *jsonRowWriter) WriteHeader(columns []string) error {
+	j.columns = columns
+	if j.jsonl {
+		return nil
+	}
+	_, err := io.WriteString(j.w, "[")
+	return err
+}
+
+func (j *jsonRowWriter) WriteRow(values []interface{}) error {
+	if !j.jsonl && j.wroteRow {
+		if _, err := io.WriteString(j.w, ","); err != nil {
+			return err
+		}
+	}
+	j.wroteRow = true
+
+	fields := make(map[string]interface{}, len(values))
+	for i, v := range values {
+		typed, err := typedJSONValue(j.columnTypes[i], v)
+		if err != nil {
+			return fmt.Errorf("converting column %q: %w", j.columns[i], err)
+		}
+		fields[j.columns[i]] = typed
+	}
+
+	// Marshal the fields individually (rather than the map directly) so the
+	// JSON object preserves the query's column order instead of the
+	// alphabetical key order json.Marshal uses for a map.
+	if _, err := io.WriteString(j.w, "{"); err != nil {
+		return err
+	}
+	for i, col := range j.columns {
+		if i > 0 {
+			if _, err := io.WriteString(j.w, ","); err != nil {
+				return err
+			}
+		}
+		keyBytes, err := json.Marshal(col)
+		if err != nil {
+			return err
+		}
+		valBytes, err := json.Marshal(fields[col])
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(j.w, "%s:%s", keyBytes, valBytes); err != nil {
+			return err
+		}
+	}
+	if _, err := io.WriteString(j.w, "}"); err != nil {
+		return err
+	}
+
+	if j.jsonl {
+		_, err := io.WriteString(j.w, "\n")
+		return err
+	}
+	return nil
+}
+
+func (j *jsonRowWriter) Close() error {
+	if j.jsonl {
+		return nil
+	}
+	_, err := io.WriteString(j.w, "]\n")
+	return err
+}
+
+// typedJSONValue converts a scanned column value into a JSON-friendly Go
+// value based on the column's underlying Snowflake type (via
+// SnowflakeType/DatabaseTypeName), so numbers and booleans aren't
+// stringified, timestamps become RFC3339 strings, and VARIANT/OBJECT/ARRAY
+// columns are embedded as raw JSON rather than being double-encoded as a
+// JSON string.
+//
+// The driver scans FIXED (NUMBER) and REAL (FLOAT/DOUBLE) columns as plain
+// strings, not Go numeric types, so those need an explicit string
+// conversion here; without it they'd be marshaled as quoted JSON strings
+// instead of JSON numbers.
+func typedJSONValue(ct *sql.ColumnType, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	switch strings.ToUpper(ct.DatabaseTypeName()) {
+	case "TIMESTAMP_LTZ", "TIMESTAMP_NTZ", "TIMESTAMP_TZ", "DATE", "TIME":
+		if t, ok := v.(time.Time); ok {
+			return t.Format(time.RFC3339Nano), nil
+		}
+	case "FIXED":
+		if s, ok := v.(string); ok {
+			if _, scale, ok := ct.DecimalSize(); ok && scale == 0 {
+				return strconv.ParseInt(s, 10, 64)
+			}
+			return strconv.ParseFloat(s, 64)
+		}
+	case "REAL":
+		if s, ok := v.(string); ok {
+			return strconv.ParseFloat(s, 64)
+		}
+	case "VARIANT", "OBJECT", "ARRAY":
+		switch raw := v.(type) {
+		case string:
+			return json.RawMessage(raw), nil
+		case []byte:
+			return json.RawMessage(raw), nil
+		}
+	}
+
+	return v, nil
+}
+
+// parquetRowWriter writes rows as Parquet, using a schema derived from the
+// query's column types so numeric and timestamp columns keep a typed
+// representation instead of being stringified.
+type parquetRowWriter struct {
+	w       *parquet.Writer
+	types   []*sql.ColumnType
+	columns []string
+}
+
+func newParquetRowWriter(out io.Writer, columnTypes []*sql.ColumnType) *parquetRowWriter {
+	group := make(parquet.Group, len(columnTypes))
+	for _, ct := range columnTypes {
+		group[ct.Name()] = parquet.Optional(parquetNodeFor(ct))
+	}
+
+	return &parquetRowWriter{
+		w:     parquet.NewWriter(out, parquet.NewSchema("row", group)),
+		types: columnTypes,
+	}
+}
+
+// parquetNodeFor maps a Snowflake column type to the closest Parquet leaf
+// type, falling back to a string column for anything not recognised (e.g.
+// VARIANT/OBJECT/ARRAY, which are written as their raw JSON text).
+func parquetNodeFor(ct *sql.ColumnType) parquet.Node {
+	switch strings.ToUpper(ct.DatabaseTypeName()) {
+	case "FIXED":
+		if _, scale, ok := ct.DecimalSize(); ok && scale == 0 {
+			return parquet.Leaf(parquet.Int64Type)
+		}
+		return parquet.Leaf(parquet.DoubleType)
+	case "REAL":
+		return parquet.Leaf(parquet.DoubleType)
+	case "BOOLEAN":
+		return parquet.Leaf(parquet.BooleanType)
+	case "TIMESTAMP_LTZ", "TIMESTAMP_NTZ", "TIMESTAMP_TZ", "DATE", "TIME":
+		return parquet.Timestamp(parquet.Nanosecond)
+	default:
+		return parquet.String()
+	}
+}
+
+func (p *parquetRowWriter) WriteHeader(columns []string) error {
+	p.columns = columns
+	return nil
+}
+
+func (p *parquetRowWriter) WriteRow(values []interface{}) error {
+	row := make(map[string]interface{}, len(values))
+	for i, v := range values {
+		typed, err := typedParquetValue(p.types[i], v)
+		if err != nil {
+			return fmt.Errorf("converting column %q: %w", p.columns[i], err)
+		}
+		row[p.columns[i]] = typed
+	}
+	return p.w.Write(row)
+}
+
+func (p *parquetRowWriter) Close() error {
+	return p.w.Close()
+}
+
+// typedParquetValue converts a scanned column value to match the Parquet
+// leaf type chosen by parquetNodeFor for the same column. FIXED and REAL
+// columns are scanned as plain strings by the driver, so they need explicit
+// conversion to the int64/float64 Go types parquet-go requires for
+// Int64Type/DoubleType leaves; passing the string through untouched panics
+// in parquet-go's value encoding.
+func typedParquetValue(ct *sql.ColumnType, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	switch strings.ToUpper(ct.DatabaseTypeName()) {
+	case "FIXED":
+		if _, scale, ok := ct.DecimalSize(); ok && scale == 0 {
+			switch n := v.(type) {
+			case int64:
+				return n, nil
+			case float64:
+				return int64(n), nil
+			case string:
+				return strconv.ParseInt(n, 10, 64)
+			}
+		}
+		switch n := v.(type) {
+		case float64:
+			return n, nil
+		case int64:
+			return float64(n), nil
+		case string:
+			return strconv.ParseFloat(n, 64)
+		}
+	case "REAL":
+		switch n := v.(type) {
+		case float64:
+			return n, nil
+		case string:
+			return strconv.ParseFloat(n, 64)
+		}
+	}
+
+	return v, nil
+}
@@ -0,0 +1,191 @@
+package output
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+)
+
+// fakeColumn describes one column of a fakeRows result set, giving just
+// enough information for database/sql to build a real *sql.ColumnType via
+// rows.ColumnTypes() without needing a Snowflake connection.
+type fakeColumn struct {
+	name         string
+	databaseType string
+	hasDecimal   bool
+	scale        int64
+	value        driver.Value
+}
+
+type fakeDriver struct {
+	columns []fakeColumn
+}
+
+func (d fakeDriver) Open(name string) (driver.Conn, error) {
+	return fakeConn{columns: d.columns}, nil
+}
+
+type fakeConn struct {
+	columns []fakeColumn
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeStmt{columns: c.columns}, nil
+}
+func (c fakeConn) Close() error              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) { return nil, sql.ErrTxDone }
+
+type fakeStmt struct {
+	columns []fakeColumn
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, sql.ErrNoRows
+}
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeRows{columns: s.columns}, nil
+}
+
+type fakeRows struct {
+	columns []fakeColumn
+	read    bool
+}
+
+func (r *fakeRows) Columns() []string {
+	names := make([]string, len(r.columns))
+	for i, c := range r.columns {
+		names[i] = c.name
+	}
+	return names
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.read {
+		return sql.ErrNoRows
+	}
+	r.read = true
+	for i, c := range r.columns {
+		dest[i] = c.value
+	}
+	return nil
+}
+
+func (r *fakeRows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.columns[index].databaseType
+}
+
+func (r *fakeRows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	c := r.columns[index]
+	return 38, c.scale, c.hasDecimal
+}
+
+// columnTypesFor runs a single query against a fake driver and returns the
+// resulting *sql.ColumnType/value pairs, giving typedJSONValue and
+// typedParquetValue a real sql.ColumnType to work against.
+func columnTypesFor(t *testing.T, columns []fakeColumn) ([]*sql.ColumnType, []interface{}) {
+	t.Helper()
+
+	driverName := "fake-" + t.Name()
+	sql.Register(driverName, fakeDriver{columns: columns})
+
+	db, err := sql.Open(driverName, "")
+	if err != nil {
+		t.Fatalf("opening fake db: %v", err)
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(context.Background(), "select")
+	if err != nil {
+		t.Fatalf("querying fake db: %v", err)
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatalf("getting column types: %v", err)
+	}
+
+	cols := make([]string, len(columns))
+	for i, c := range columns {
+		cols[i] = c.name
+	}
+	dest := make([]interface{}, len(cols))
+	ptrs := make([]interface{}, len(cols))
+	for i := range dest {
+		ptrs[i] = &dest[i]
+	}
+	if !rows.Next() {
+		t.Fatalf("expected a row")
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		t.Fatalf("scanning row: %v", err)
+	}
+
+	return columnTypes, dest
+}
+
+func TestTypedJSONValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		column fakeColumn
+		want   interface{}
+	}{
+		{"fixed zero scale", fakeColumn{name: "n", databaseType: "FIXED", hasDecimal: true, scale: 0, value: "42"}, int64(42)},
+		{"fixed with scale", fakeColumn{name: "n", databaseType: "FIXED", hasDecimal: true, scale: 2, value: "42.50"}, float64(42.5)},
+		{"real", fakeColumn{name: "n", databaseType: "REAL", value: "1.5"}, float64(1.5)},
+		{"variant", fakeColumn{name: "n", databaseType: "VARIANT", value: `{"a":1}`}, json.RawMessage(`{"a":1}`)},
+		{"text passthrough", fakeColumn{name: "n", databaseType: "TEXT", value: "hello"}, "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columnTypes, values := columnTypesFor(t, []fakeColumn{tt.column})
+			got, err := typedJSONValue(columnTypes[0], values[0])
+			if err != nil {
+				t.Fatalf("typedJSONValue: %v", err)
+			}
+			if want, ok := tt.want.(json.RawMessage); ok {
+				raw, ok := got.(json.RawMessage)
+				if !ok || string(raw) != string(want) {
+					t.Fatalf("got %v (%T), want json.RawMessage(%s)", got, got, want)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypedParquetValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		column fakeColumn
+		want   interface{}
+	}{
+		{"fixed zero scale", fakeColumn{name: "n", databaseType: "FIXED", hasDecimal: true, scale: 0, value: "42"}, int64(42)},
+		{"fixed with scale", fakeColumn{name: "n", databaseType: "FIXED", hasDecimal: true, scale: 2, value: "42.50"}, float64(42.5)},
+		{"real", fakeColumn{name: "n", databaseType: "REAL", value: "1.5"}, float64(1.5)},
+		{"text passthrough", fakeColumn{name: "n", databaseType: "TEXT", value: "hello"}, "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columnTypes, values := columnTypesFor(t, []fakeColumn{tt.column})
+			got, err := typedParquetValue(columnTypes[0], values[0])
+			if err != nil {
+				t.Fatalf("typedParquetValue: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+// Package query resolves SQL and its bind parameters, executes them against
+// an open Snowflake connection, and streams each statement's result set to
+// an output.RowWriter.
+package query
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/snowflakedb/gosnowflake"
+
+	"github.com/tekumara/snowcat/pkg/output"
+)
+
+// Options controls how a query or statement is submitted to Snowflake.
+type Options struct {
+	// MultiStatement executes up to N statements in a single request (via
+	// gosnowflake.WithMultiStatement), surfacing each statement's results as
+	// its own result set. 0 disables multi-statement mode.
+	MultiStatement int
+	// QueryID re-attaches to a previously submitted query's results instead
+	// of executing a new one.
+	QueryID string
+}
+
+// WithOptions layers opts onto ctx via the relevant gosnowflake context
+// helpers, returning the context to use for the query/exec call.
+func WithOptions(ctx context.Context, opts Options) (context.Context, error) {
+	if opts.MultiStatement > 0 {
+		var err error
+		ctx, err = gosnowflake.WithMultiStatement(ctx, opts.MultiStatement)
+		if err != nil {
+			return nil, fmt.Errorf("enabling multi-statement mode: %w", err)
+		}
+	}
+	if opts.QueryID != "" {
+		ctx = gosnowflake.WithFetchResultByID(ctx, opts.QueryID)
+	}
+	return ctx, nil
+}
+
+// QueryAsync submits query asynchronously and returns its Snowflake query ID
+// immediately, without waiting for results. database/sql has no way to
+// expose the driver's SnowflakeRows from a normal db.QueryContext call, so
+// this drops down to the raw driver connection to issue the query and read
+// the query ID back off it directly.
+func QueryAsync(ctx context.Context, db *sql.DB, query string, args []interface{}) (string, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting connection: %w", err)
+	}
+	defer conn.Close()
+
+	namedArgs, err := driverNamedValues(args)
+	if err != nil {
+		return "", err
+	}
+
+	ctx = gosnowflake.WithAsyncMode(ctx)
+
+	var queryID string
+	err = conn.Raw(func(raw interface{}) error {
+		queryer, ok := raw.(driver.QueryerContext)
+		if !ok {
+			return fmt.Errorf("driver connection does not support QueryerContext")
+		}
+
+		rows, err := queryer.QueryContext(ctx, query, namedArgs)
+		if err != nil {
+			return fmt.Errorf("querying snowflake: %w", err)
+		}
+		defer rows.Close()
+
+		sfRows, ok := rows.(gosnowflake.SnowflakeRows)
+		if !ok {
+			return fmt.Errorf("driver rows do not support retrieving a query ID")
+		}
+		queryID = sfRows.GetQueryID()
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return queryID, nil
+}
+
+// driverNamedValues converts the sql.Named bind arguments produced by
+// NamedArgs into the driver.NamedValue form required by
+// driver.QueryerContext.QueryContext.
+func driverNamedValues(args []interface{}) ([]driver.NamedValue, error) {
+	namedArgs := make([]driver.NamedValue, len(args))
+	for i, a := range args {
+		na, ok := a.(sql.NamedArg)
+		if !ok {
+			return nil, fmt.Errorf("argument %d is not a sql.Named value", i)
+		}
+		namedArgs[i] = driver.NamedValue{Name: na.Name, Ordinal: i + 1, Value: na.Value}
+	}
+	return namedArgs, nil
+}
+
+// Ping runs SELECT 1 against db and returns the round-trip latency, as a
+// lightweight auth+network smoke test.
+func Ping(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	start := time.Now()
+	var one int
+	if err := db.QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+// ResolveQuery returns the SQL to execute: query if set, otherwise the
+// contents of queryFile, otherwise everything read from stdin. Setting both
+// query and queryFile is an error.
+func ResolveQuery(query, queryFile string) (string, error) {
+	if query != "" && queryFile != "" {
+		return "", fmt.Errorf("only one of --query or --query.file may be set")
+	}
+
+	if query != "" {
+		return query, nil
+	}
+
+	if queryFile != "" {
+		b, err := os.ReadFile(queryFile)
+		if err != nil {
+			return "", fmt.Errorf("reading %s: %w", queryFile, err)
+		}
+		return string(b), nil
+	}
+
+	b, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("reading stdin: %w", err)
+	}
+	return string(b), nil
+}
+
+// ReadParamFile reads name=value query parameters from a file, one per
+// line. Blank lines and lines starting with # are ignored.
+func ReadParamFile(path string) ([]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var params []string
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		params = append(params, line)
+	}
+	return params, nil
+}
+
+// ResolveArgs combines params (e.g. from repeated --param flags) with any
+// name=value parameters in paramFile (--param.file), then parses the result
+// into bind arguments suitable for sql.QueryContext/ExecContext.
+func ResolveArgs(params []string, paramFile string) ([]interface{}, error) {
+	if paramFile != "" {
+		fileParams, err := ReadParamFile(paramFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading param.file: %w", err)
+		}
+		params = append(params, fileParams...)
+	}
+	return NamedArgs(params)
+}
+
+// NamedArgs parses "name=value" query parameters into bind arguments
+// suitable for sql.QueryContext/ExecContext, using sql.Named so they can be
+// referenced by name in the query.
+func NamedArgs(params []string) ([]interface{}, error) {
+	args := make([]interface{}, 0, len(params))
+	for _, p := range params {
+		name, value, ok := strings.Cut(p, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --param %q: expected name=value", p)
+		}
+		args = append(args, sql.Named(name, value))
+	}
+	return args, nil
+}
+
+// WriteResultSet writes the current result set of rows to the requested
+// output format, returning the number of rows written. When
+// templatedOutputFile is set, the statement's results are written to their
+// own file (outputFile with stmt substituted for its %d verb) instead of
+// out.
+func WriteResultSet(rows *sql.Rows, out io.Writer, outputFile, outputFormat string, stmt int, templatedOutputFile bool) (int, error) {
+	dest := out
+	if templatedOutputFile {
+		f, err := os.Create(fmt.Sprintf(outputFile, stmt))
+		if err != nil {
+			return 0, fmt.Errorf("creating output file: %w", err)
+		}
+		defer f.Close()
+		dest = f
+	} else if stmt > 0 {
+		// Separate sections of a multi-statement query sharing a single stream
+		if _, err := io.WriteString(out, "\n"); err != nil {
+			return 0, err
+		}
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, fmt.Errorf("getting column names: %w", err)
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, fmt.Errorf("getting column types: %w", err)
+	}
+
+	w, err := output.NewWriter(outputFormat, dest, columnTypes)
+	if err != nil {
+		return 0, fmt.Errorf("creating row writer: %w", err)
+	}
+
+	if err := w.WriteHeader(columns); err != nil {
+		return 0, fmt.Errorf("writing output header: %w", err)
+	}
+
+	// Create a slice of interface{} to store the row values
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	rowCount := 0
+	for rows.Next() {
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return rowCount, fmt.Errorf("scanning row: %w", err)
+		}
+
+		// Write the row, letting the writer decide how to render each value
+		if err := w.WriteRow(values); err != nil {
+			return rowCount, fmt.Errorf("writing row: %w", err)
+		}
+		rowCount++
+	}
+
+	if err := w.Close(); err != nil {
+		return rowCount, fmt.Errorf("closing output writer: %w", err)
+	}
+
+	return rowCount, nil
+}
@@ -0,0 +1,71 @@
+package query
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNamedArgs(t *testing.T) {
+	args, err := NamedArgs([]string{"a=1", "b=hello"})
+	if err != nil {
+		t.Fatalf("NamedArgs: %v", err)
+	}
+	if len(args) != 2 {
+		t.Fatalf("expected 2 args, got %d", len(args))
+	}
+
+	a, ok := args[0].(sql.NamedArg)
+	if !ok {
+		t.Fatalf("expected sql.NamedArg, got %T", args[0])
+	}
+	if a.Name != "a" || a.Value != "1" {
+		t.Errorf("got %+v, want name=a value=1", a)
+	}
+
+	b := args[1].(sql.NamedArg)
+	if b.Name != "b" || b.Value != "hello" {
+		t.Errorf("got %+v, want name=b value=hello", b)
+	}
+}
+
+func TestNamedArgsInvalid(t *testing.T) {
+	if _, err := NamedArgs([]string{"noequals"}); err == nil {
+		t.Fatal("expected an error for a param with no '='")
+	}
+}
+
+func TestResolveQuery(t *testing.T) {
+	t.Run("query takes precedence", func(t *testing.T) {
+		got, err := ResolveQuery("select 1", "")
+		if err != nil {
+			t.Fatalf("ResolveQuery: %v", err)
+		}
+		if got != "select 1" {
+			t.Errorf("got %q, want %q", got, "select 1")
+		}
+	})
+
+	t.Run("reads from queryFile", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "query.sql")
+		if err := os.WriteFile(path, []byte("select 2"), 0o600); err != nil {
+			t.Fatalf("writing temp file: %v", err)
+		}
+
+		got, err := ResolveQuery("", path)
+		if err != nil {
+			t.Fatalf("ResolveQuery: %v", err)
+		}
+		if got != "select 2" {
+			t.Errorf("got %q, want %q", got, "select 2")
+		}
+	})
+
+	t.Run("rejects both query and queryFile", func(t *testing.T) {
+		if _, err := ResolveQuery("select 1", "query.sql"); err == nil {
+			t.Fatal("expected an error when both --query and --query.file are set")
+		}
+	})
+}
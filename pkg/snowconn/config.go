@@ -0,0 +1,292 @@
+// Package snowconn resolves snowcat's snowflake.* settings (from flags, a
+// named connections.toml connection, and defaults) into a Snowflake driver
+// DSN or an open *sql.DB, including loading and decrypting private keys for
+// key-pair auth.
+//
+// adapted from https://github.com/DavidBrown-niche/gosnowflake-example/tree/0bcc7a5
+package snowconn
+
+import (
+	"crypto/rsa"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/snowflakedb/gosnowflake"
+	"go.step.sm/crypto/pemutil"
+)
+
+// Config mirrors the snowflake.* flags snowcat exposes, before any
+// connections.toml values have been merged in via ApplyConnection.
+type Config struct {
+	Account            string
+	Host               string
+	Port               int
+	Protocol           string
+	Database           string
+	Schema             string
+	Warehouse          string
+	User               string
+	Password           string
+	Role               string
+	PrivateKeyFile     string
+	PrivateKeyPasscode string
+	Authenticator      string
+	MaxRetryCount      int
+	OauthToken         string
+	OauthTokenFile     string
+}
+
+// ConnectionSettings mirrors the subset of fields in a named connection
+// section of a Snowflake CLI connections.toml file that snowcat understands.
+// See https://docs.snowflake.com/en/developer-guide/snowflake-cli/connecting/configure-connections
+type ConnectionSettings struct {
+	Account              string `toml:"account"`
+	Host                 string `toml:"host"`
+	Port                 int    `toml:"port"`
+	Protocol             string `toml:"protocol"`
+	Database             string `toml:"database"`
+	Schema               string `toml:"schema"`
+	Warehouse            string `toml:"warehouse"`
+	User                 string `toml:"user"`
+	Password             string `toml:"password"`
+	Role                 string `toml:"role"`
+	PrivateKeyFile       string `toml:"private_key_file"`
+	PrivateKeyPassphrase string `toml:"private_key_passphrase"`
+	Authenticator        string `toml:"authenticator"`
+}
+
+// LoadConnection reads the named connection from the connections.toml file
+// found under SNOWFLAKE_HOME (default: ~/.snowflake). If the file does not
+// exist and name was not explicitly requested by the caller (via flag or
+// SNOWFLAKE_DEFAULT_CONNECTION_NAME), it returns a nil ConnectionSettings
+// rather than an error so snowcat keeps working purely off snowflake.* flags.
+func LoadConnection(name string, required bool) (*ConnectionSettings, error) {
+	home := os.Getenv("SNOWFLAKE_HOME")
+	if home == "" {
+		dir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("determining home directory: %w", err)
+		}
+		home = filepath.Join(dir, ".snowflake")
+	}
+
+	path := filepath.Join(home, "connections.toml")
+	var connections map[string]ConnectionSettings
+	if _, err := toml.DecodeFile(path, &connections); err != nil {
+		if os.IsNotExist(err) && !required {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	conn, ok := connections[name]
+	if !ok {
+		return nil, fmt.Errorf("connection %q not found in %s", name, path)
+	}
+	return &conn, nil
+}
+
+// ApplyConnection fills in any fields of c left unset with the
+// corresponding value from conn. Flags always take precedence over a named
+// connection's settings. portSet and protocolSet must report whether
+// --snowflake.port / --snowflake.protocol were explicitly passed by the
+// caller: unlike the other fields, their flag defaults (443, "https") are
+// valid values a user might genuinely want, so c.Port/c.Protocol alone
+// can't tell "left at the default" apart from "explicitly set to the
+// default". It is a no-op if conn is nil.
+func (c *Config) ApplyConnection(conn *ConnectionSettings, portSet, protocolSet bool) {
+	if conn == nil {
+		return
+	}
+	if c.Account == "" {
+		c.Account = conn.Account
+	}
+	if c.Host == "" {
+		c.Host = conn.Host
+	}
+	if !portSet && conn.Port != 0 {
+		c.Port = conn.Port
+	}
+	if !protocolSet && conn.Protocol != "" {
+		c.Protocol = conn.Protocol
+	}
+	if c.Database == "" {
+		c.Database = conn.Database
+	}
+	if c.Schema == "" {
+		c.Schema = conn.Schema
+	}
+	if c.Warehouse == "" {
+		c.Warehouse = conn.Warehouse
+	}
+	if c.User == "" {
+		c.User = conn.User
+	}
+	if c.Password == "" {
+		c.Password = conn.Password
+	}
+	if c.Role == "" {
+		c.Role = conn.Role
+	}
+	if c.PrivateKeyFile == "" {
+		c.PrivateKeyFile = conn.PrivateKeyFile
+	}
+	if c.PrivateKeyPasscode == "" {
+		c.PrivateKeyPasscode = conn.PrivateKeyPassphrase
+	}
+	if c.Authenticator == "" {
+		c.Authenticator = conn.Authenticator
+	}
+}
+
+// Validate checks that the required fields and exactly one authentication
+// method are set.
+func (c Config) Validate() error {
+	var missingFlags []string
+	if c.Account == "" {
+		missingFlags = append(missingFlags, "snowflake.account")
+	}
+	if c.User == "" {
+		missingFlags = append(missingFlags, "snowflake.user")
+	}
+
+	if c.OauthToken != "" && c.OauthTokenFile != "" {
+		return fmt.Errorf("must provide at most one of snowflake.oauth.token or snowflake.oauth.token.file")
+	}
+	oauthProvided := c.OauthToken != "" || c.OauthTokenFile != ""
+
+	authMethodCount := 0
+	if c.Password != "" {
+		authMethodCount++
+	}
+	if c.PrivateKeyFile != "" {
+		authMethodCount++
+	}
+	if oauthProvided {
+		authMethodCount++
+	}
+	if c.Authenticator != "" {
+		authMethodCount++
+	}
+	if authMethodCount == 0 {
+		missingFlags = append(missingFlags, "authentication method (one of: snowflake.password, snowflake.private.key.file, snowflake.oauth.token(.file), or snowflake.authenticator)")
+	}
+	if authMethodCount > 1 {
+		return fmt.Errorf("must provide exactly one authentication method (password provided: %v, private key provided: %v, oauth token provided: %v, authenticator provided: %v)",
+			c.Password != "", c.PrivateKeyFile != "", oauthProvided, c.Authenticator != "")
+	}
+
+	if len(missingFlags) > 0 {
+		return fmt.Errorf("missing required flags: %s", strings.Join(missingFlags, ", "))
+	}
+
+	if c.Protocol != "https" && c.Protocol != "http" {
+		return fmt.Errorf("protocol must be either 'http' or 'https', got %q", c.Protocol)
+	}
+
+	return nil
+}
+
+// DSN resolves authentication (loading and decrypting the private key, or
+// reading the OAuth token file, where required) and returns a DSN suitable
+// for sql.Open("snowflake", ...).
+func (c Config) DSN() (string, error) {
+	cfg := gosnowflake.Config{
+		Account:       c.Account,
+		User:          c.User,
+		Database:      c.Database,
+		Schema:        c.Schema,
+		Warehouse:     c.Warehouse,
+		Role:          c.Role,
+		Host:          c.Host,
+		Port:          c.Port,
+		Protocol:      c.Protocol,
+		MaxRetryCount: c.MaxRetryCount,
+	}
+
+	// Now add either private key, password, oauth, or one of the URL-based
+	// authenticators (externalbrowser, native Okta) depending on which
+	// fields were set.
+	switch {
+	case c.Password != "":
+		cfg.Authenticator = gosnowflake.AuthTypeSnowflake
+		cfg.Password = c.Password
+	case c.PrivateKeyFile != "":
+		rsaKey, err := loadPrivateKey(c.PrivateKeyFile, c.PrivateKeyPasscode)
+		if err != nil {
+			return "", err
+		}
+		cfg.Authenticator = gosnowflake.AuthTypeJwt
+		cfg.PrivateKey = rsaKey
+	case c.OauthToken != "" || c.OauthTokenFile != "":
+		token := c.OauthToken
+		if c.OauthTokenFile != "" {
+			tokenBytes, err := os.ReadFile(c.OauthTokenFile)
+			if err != nil {
+				return "", fmt.Errorf("reading OAuth token file: %w", err)
+			}
+			token = strings.TrimSpace(string(tokenBytes))
+		}
+		cfg.Authenticator = gosnowflake.AuthTypeOAuth
+		cfg.Token = token
+	case c.Authenticator == "externalbrowser":
+		cfg.Authenticator = gosnowflake.AuthTypeExternalBrowser
+	case strings.HasPrefix(c.Authenticator, "https://"):
+		oktaURL, err := url.Parse(c.Authenticator)
+		if err != nil {
+			return "", fmt.Errorf("invalid Okta URL %q: %w", c.Authenticator, err)
+		}
+		cfg.Authenticator = gosnowflake.AuthTypeOkta
+		cfg.OktaURL = oktaURL
+	default:
+		return "", fmt.Errorf("invalid authenticator %q", c.Authenticator)
+	}
+
+	dsn, err := gosnowflake.DSN(&cfg)
+	if err != nil {
+		return "", fmt.Errorf("creating DSN from config: %w", err)
+	}
+	return dsn, nil
+}
+
+// Open builds a DSN from c and opens a *sql.DB for it.
+func (c Config) Open() (*sql.DB, error) {
+	dsn, err := c.DSN()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("snowflake", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to snowflake: %w", err)
+	}
+	return db, nil
+}
+
+// loadPrivateKey reads and decrypts (if necessary) a pkcs8 PEM-encoded RSA
+// private key used for key-pair (JWT) authentication.
+//
+// Unfortunately need to use a third party package for this because the std
+// crypto package does not support decrypting pkcs8 keys.
+func loadPrivateKey(path, passcode string) (*rsa.PrivateKey, error) {
+	key, err := pemutil.Read(
+		path,
+		// Can pass the passcode even if it's not set (indicating the key is
+		// not encrypted), decryption will just be skipped in that case
+		pemutil.WithPassword([]byte(passcode)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,78 @@
+package snowconn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/snowflakedb/gosnowflake"
+)
+
+// ConfigureLogging applies level (one of: off, error, warn, info, debug,
+// trace) to both zerolog's global level and the snowflake driver's own
+// logger, so snowcat and the driver log at one consistent verbosity.
+func ConfigureLogging(level string) error {
+	zlevel, err := parseLogLevel(level)
+	if err != nil {
+		return err
+	}
+	zerolog.SetGlobalLevel(zlevel)
+
+	if err := gosnowflake.GetLogger().SetLogLevel(level); err != nil {
+		return fmt.Errorf("setting snowflake driver log level: %w", err)
+	}
+	return nil
+}
+
+// parseLogLevel maps a log level string to the equivalent zerolog.Level.
+// The same string is also passed verbatim to
+// gosnowflake.GetLogger().SetLogLevel, so the accepted values are the ones
+// the snowflake driver understands, not zerolog's own level names.
+func parseLogLevel(level string) (zerolog.Level, error) {
+	switch strings.ToLower(level) {
+	case "off":
+		return zerolog.Disabled, nil
+	case "error":
+		return zerolog.ErrorLevel, nil
+	case "warn":
+		return zerolog.WarnLevel, nil
+	case "info":
+		return zerolog.InfoLevel, nil
+	case "debug":
+		return zerolog.DebugLevel, nil
+	case "trace":
+		return zerolog.TraceLevel, nil
+	default:
+		return zerolog.NoLevel, fmt.Errorf("unsupported log level %q (must be one of: off, error, warn, info, debug, trace)", level)
+	}
+}
+
+// RegisterRequestContext attaches requestID (and user/role/warehouse, where
+// set) to every driver log line for the lifetime of the process, so
+// snowcat's own logs and the driver's logs can be correlated back to a
+// single run when debugging a failed query in production.
+//
+// gosnowflake.RegisterLogContextHook registers one hook per context key, each
+// returning a single string, so one call is needed per field rather than a
+// single hook returning a map.
+func RegisterRequestContext(requestID, user, role, warehouse string) {
+	gosnowflake.RegisterLogContextHook("request_id", func(ctx context.Context) string {
+		return requestID
+	})
+	if user != "" {
+		gosnowflake.RegisterLogContextHook("user", func(ctx context.Context) string {
+			return user
+		})
+	}
+	if role != "" {
+		gosnowflake.RegisterLogContextHook("role", func(ctx context.Context) string {
+			return role
+		})
+	}
+	if warehouse != "" {
+		gosnowflake.RegisterLogContextHook("warehouse", func(ctx context.Context) string {
+			return warehouse
+		})
+	}
+}